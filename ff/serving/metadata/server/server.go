@@ -1,8 +1,11 @@
 package main
 
 import (
+	"time"
+
 	"github.com/featureform/serving/metadata/search"
 
+	"github.com/featureform/provider"
 	"github.com/featureform/serving/metadata"
 	"go.uber.org/zap"
 )
@@ -17,6 +20,15 @@ func main() {
 			},
 		},
 	}
+
+	// providerRegistry is shared across every request the metadata server
+	// handles, so a Redis/Cassandra/DynamoDB connection a resource's
+	// provider needs is dialed once and reused rather than once per
+	// request. CloseAll releases them (and stops the health check loop) on
+	// shutdown.
+	providerRegistry := provider.NewRegistry(30 * time.Second)
+	defer providerRegistry.CloseAll()
+
 	config := &metadata.Config{
 		Logger:  logger,
 		Address: addr,
@@ -25,7 +37,8 @@ func main() {
 			Host:   "localhost",
 			ApiKey: "xyz",
 		},
-		StorageProvider: storageProvider,
+		StorageProvider:  storageProvider,
+		ProviderRegistry: providerRegistry,
 	}
 	server, err := metadata.NewMetadataServer(config)
 	if err != nil {
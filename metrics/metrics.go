@@ -0,0 +1,48 @@
+// This Source Code Form is subject to the terms of the Mozilla Public
+// License, v. 2.0. If a copy of the MPL was not distributed with this
+// file, You can obtain one at https://mozilla.org/MPL/2.0/.
+
+// Package metrics defines the small metrics-emission surface that provider
+// backends are handed at construction time, so a Redis/Cassandra/DynamoDB
+// store can report request counts and latencies without knowing whether
+// those numbers end up in Prometheus, StatsD, or nowhere at all.
+package metrics
+
+// Sink is the metrics facade threaded into provider factories via
+// provider.WithMetrics. Implementations are expected to be safe for
+// concurrent use, since a single Sink is typically shared across all
+// providers created by a process.
+type Sink interface {
+	Counter(name string, tags ...string) Counter
+	Gauge(name string, tags ...string) Gauge
+}
+
+// Counter is a monotonically increasing value, e.g. requests served or
+// errors encountered.
+type Counter interface {
+	Inc()
+	Add(delta float64)
+}
+
+// Gauge is a value that can move up or down, e.g. open connections.
+type Gauge interface {
+	Set(value float64)
+}
+
+// Nop is a Sink whose Counters and Gauges discard every observation. It is
+// the default used when a provider is constructed without WithMetrics.
+var Nop Sink = nopSink{}
+
+type nopSink struct{}
+
+func (nopSink) Counter(name string, tags ...string) Counter { return nopCounter{} }
+func (nopSink) Gauge(name string, tags ...string) Gauge     { return nopGauge{} }
+
+type nopCounter struct{}
+
+func (nopCounter) Inc()              {}
+func (nopCounter) Add(delta float64) {}
+
+type nopGauge struct{}
+
+func (nopGauge) Set(value float64) {}
@@ -0,0 +1,303 @@
+// This Source Code Form is subject to the terms of the Mozilla Public
+// License, v. 2.0. If a copy of the MPL was not distributed with this
+// file, You can obtain one at https://mozilla.org/MPL/2.0/.
+
+package provider
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"github.com/gocql/gocql"
+)
+
+// cassandraOnlineStore holds the live session the factory dialed. The
+// session is unexported: CassandraConfig only ever carries the fields
+// needed to build one, never the connection itself.
+type cassandraOnlineStore struct {
+	BaseProvider
+	config  CassandraConfig
+	session *gocql.Session
+}
+
+func cassandraOnlineStoreFactory(opts providerOptions) (Provider, error) {
+	config := CassandraConfig{}
+	if err := config.Deserialize(opts.Config()); err != nil {
+		return nil, fmt.Errorf("cassandra: could not deserialize config: %w", err)
+	}
+	if len(config.Hosts) == 0 {
+		return nil, fmt.Errorf("cassandra: config must list at least one host")
+	}
+
+	cluster := newCassandraClusterConfig(config)
+	if config.Username != "" {
+		password, err := resolveCassandraPassword(opts, config)
+		if err != nil {
+			return nil, err
+		}
+		cluster.Authenticator = gocql.PasswordAuthenticator{
+			Username: config.Username,
+			Password: password,
+		}
+	}
+
+	ctx := opts.Context()
+	if err := ensureCassandraKeyspace(ctx, *cluster, config); err != nil {
+		opts.Logger().Errorw("cassandra: could not create keyspace", "hosts", config.Hosts, "keyspace", config.Keyspace, "err", err)
+		return nil, err
+	}
+
+	cluster.Keyspace = config.Keyspace
+	session, err := createSessionWithDeadline(ctx, *cluster)
+	if err != nil {
+		opts.Logger().Errorw("cassandra: could not dial cluster", "hosts", config.Hosts, "err", err)
+		return nil, fmt.Errorf("cassandra: could not create session: %w", err)
+	}
+
+	return &cassandraOnlineStore{
+		BaseProvider: NewBaseProvider(CassandraOnline, opts),
+		config:       config,
+		session:      session,
+	}, nil
+}
+
+// newCassandraClusterConfig builds the gocql.ClusterConfig shared by both
+// the bootstrap session (used to create the keyspace) and the real one, so
+// the two never drift out of sync on pool/consistency/TLS settings.
+func newCassandraClusterConfig(config CassandraConfig) *gocql.ClusterConfig {
+	cluster := gocql.NewCluster(config.Hosts...)
+	if config.Port != 0 {
+		cluster.Port = config.Port
+	}
+	if config.Consistency != 0 {
+		cluster.Consistency = config.Consistency
+	}
+	if config.SerialConsistency != 0 {
+		cluster.SerialConsistency = config.SerialConsistency
+	}
+	if config.NumConns > 0 {
+		cluster.NumConns = config.NumConns
+	}
+	if config.Timeout > 0 {
+		cluster.Timeout = config.Timeout
+	}
+	if config.ConnectTimeout > 0 {
+		cluster.ConnectTimeout = config.ConnectTimeout
+	}
+	// Token-aware selection keeps requests off the coordinator hop they'd
+	// otherwise take on every query across a multi-node cluster.
+	cluster.PoolConfig.HostSelectionPolicy = gocql.TokenAwareHostPolicy(gocql.RoundRobinHostPolicy())
+	if config.RetryPolicy.NumRetries > 0 {
+		cluster.RetryPolicy = &gocql.ExponentialBackoffRetryPolicy{
+			NumRetries: config.RetryPolicy.NumRetries,
+			Min:        config.RetryPolicy.Backoff,
+			Max:        config.RetryPolicy.Backoff * time.Duration(config.RetryPolicy.NumRetries),
+		}
+	}
+	if config.TLS != nil && config.TLS.Enabled {
+		cluster.SslOpts = &gocql.SslOptions{
+			CertPath:               config.TLS.CertFile,
+			KeyPath:                config.TLS.KeyFile,
+			CaPath:                 config.TLS.CAFile,
+			EnableHostVerification: !config.TLS.InsecureSkipVerify,
+		}
+	}
+	return cluster
+}
+
+func resolveCassandraPassword(opts providerOptions, config CassandraConfig) (string, error) {
+	if config.PasswordRef.Name == "" {
+		return "", fmt.Errorf("cassandra: username %q set without a PasswordRef", config.Username)
+	}
+	return ResolveCredential(opts, config.PasswordRef)
+}
+
+// ensureCassandraKeyspace connects without a keyspace selected and issues a
+// CREATE KEYSPACE IF NOT EXISTS using config.ReplicationStrategy (defaulting
+// to a replication factor of 3 under SimpleStrategy), so a fresh cluster
+// doesn't need an operator to provision the keyspace by hand first. ctx
+// bounds both the bootstrap connection and the query.
+func ensureCassandraKeyspace(ctx context.Context, cluster gocql.ClusterConfig, config CassandraConfig) error {
+	cluster.Keyspace = ""
+	session, err := createSessionWithDeadline(ctx, cluster)
+	if err != nil {
+		return fmt.Errorf("cassandra: could not connect to create keyspace: %w", err)
+	}
+	defer session.Close()
+
+	strategy := config.ReplicationStrategy
+	if strategy.Class == "" {
+		strategy = CassandraReplicationStrategy{
+			Class:   "SimpleStrategy",
+			Options: map[string]string{"replication_factor": "3"},
+		}
+	}
+	query := fmt.Sprintf(
+		"CREATE KEYSPACE IF NOT EXISTS %s WITH replication = %s",
+		config.Keyspace,
+		cassandraReplicationCQL(strategy),
+	)
+	return session.Query(query).WithContext(ctx).Exec()
+}
+
+// createSessionWithDeadline runs cluster.CreateSession in the background and
+// bounds it by ctx. gocql.ClusterConfig.CreateSession has no native context
+// support, so this is exactly the case deadlineTimer exists for: a
+// cancellation channel that closes when ctx's deadline (if any) arrives,
+// selected against alongside the dial itself.
+func createSessionWithDeadline(ctx context.Context, cluster gocql.ClusterConfig) (*gocql.Session, error) {
+	timer := newDeadlineTimer()
+	if deadline, ok := ctx.Deadline(); ok {
+		timer.SetReadDeadline(deadline)
+	}
+
+	type result struct {
+		session *gocql.Session
+		err     error
+	}
+	resultCh := make(chan result, 1)
+	go func() {
+		session, err := cluster.CreateSession()
+		resultCh <- result{session, err}
+	}()
+
+	select {
+	case res := <-resultCh:
+		return res.session, res.err
+	case <-ctx.Done():
+		return nil, ctx.Err()
+	case <-timer.readCancel():
+		return nil, fmt.Errorf("cassandra: timed out creating session")
+	}
+}
+
+func cassandraReplicationCQL(strategy CassandraReplicationStrategy) string {
+	cql := fmt.Sprintf("{'class': '%s'", strategy.Class)
+	for k, v := range strategy.Options {
+		cql += fmt.Sprintf(", '%s': '%s'", k, v)
+	}
+	cql += "}"
+	return cql
+}
+
+func (store *cassandraOnlineStore) Close() error {
+	store.session.Close()
+	return nil
+}
+
+func (store *cassandraOnlineStore) HealthCheck(ctx context.Context) error {
+	err := store.session.Query("SELECT now() FROM system.local").WithContext(ctx).Exec()
+	if err != nil {
+		store.Metrics.Counter("cassandra.health_check.failure").Inc()
+		return err
+	}
+	store.Metrics.Counter("cassandra.health_check.success").Inc()
+	return nil
+}
+
+func (store *cassandraOnlineStore) AsOnlineStore() (OnlineStore, error) {
+	return store, nil
+}
+
+// CreateTable issues a CREATE TABLE IF NOT EXISTS for the feature/variant
+// pair and hands back a table that reads and writes it. valueType is
+// unused for now - Cassandra stores every value as text - but is kept on
+// the signature so a future typed column scheme doesn't have to change it.
+func (store *cassandraOnlineStore) CreateTable(ctx context.Context, feature, variant string, valueType ValueType) (OnlineStoreTable, error) {
+	table := cassandraTableName(feature, variant)
+	query := fmt.Sprintf("CREATE TABLE IF NOT EXISTS %s (entity text PRIMARY KEY, value text)", table)
+	if err := store.session.Query(query).WithContext(ctx).Exec(); err != nil {
+		return nil, fmt.Errorf("cassandra: could not create table %s: %w", table, err)
+	}
+	return &cassandraOnlineStoreTable{session: store.session, table: table}, nil
+}
+
+func (store *cassandraOnlineStore) GetTable(ctx context.Context, feature, variant string) (OnlineStoreTable, error) {
+	table := cassandraTableName(feature, variant)
+	var name string
+	err := store.session.Query(
+		"SELECT table_name FROM system_schema.tables WHERE keyspace_name = ? AND table_name = ?",
+		store.config.Keyspace, table,
+	).WithContext(ctx).Scan(&name)
+	if err == gocql.ErrNotFound {
+		return nil, fmt.Errorf("cassandra: no table for feature %q variant %q", feature, variant)
+	}
+	if err != nil {
+		return nil, fmt.Errorf("cassandra: could not look up table %s: %w", table, err)
+	}
+	return &cassandraOnlineStoreTable{session: store.session, table: table}, nil
+}
+
+func cassandraTableName(feature, variant string) string {
+	return fmt.Sprintf("feature_%s_%s", feature, variant)
+}
+
+// cassandraOnlineStoreTable is the per-(feature,variant) table a
+// cassandraOnlineStore hands back from CreateTable/GetTable. Reads and
+// writes go through the shared session, bounded by both ctx (native gocql
+// support via WithContext) and a deadlineTimer keyed off ctx's deadline -
+// belt and suspenders so a caller-supplied deadline reliably unblocks the
+// call even if the driver's own cancellation is slow to notice ctx.Done().
+type cassandraOnlineStoreTable struct {
+	session *gocql.Session
+	table   string
+}
+
+func (t *cassandraOnlineStoreTable) Set(ctx context.Context, entity string, value interface{}) error {
+	timer := newDeadlineTimer()
+	if deadline, ok := ctx.Deadline(); ok {
+		timer.SetWriteDeadline(deadline)
+	}
+
+	query := t.session.Query(
+		fmt.Sprintf("INSERT INTO %s (entity, value) VALUES (?, ?)", t.table),
+		entity, fmt.Sprintf("%v", value),
+	).WithContext(ctx)
+
+	errCh := make(chan error, 1)
+	go func() { errCh <- query.Exec() }()
+	select {
+	case err := <-errCh:
+		return err
+	case <-ctx.Done():
+		return ctx.Err()
+	case <-timer.writeCancel():
+		return fmt.Errorf("cassandra: write to %s timed out", t.table)
+	}
+}
+
+func (t *cassandraOnlineStoreTable) Get(ctx context.Context, entity string) (interface{}, error) {
+	timer := newDeadlineTimer()
+	if deadline, ok := ctx.Deadline(); ok {
+		timer.SetReadDeadline(deadline)
+	}
+
+	query := t.session.Query(
+		fmt.Sprintf("SELECT value FROM %s WHERE entity = ?", t.table),
+		entity,
+	).WithContext(ctx)
+
+	type result struct {
+		value string
+		err   error
+	}
+	resultCh := make(chan result, 1)
+	go func() {
+		var value string
+		err := query.Scan(&value)
+		resultCh <- result{value, err}
+	}()
+
+	select {
+	case res := <-resultCh:
+		if res.err != nil {
+			return nil, res.err
+		}
+		return res.value, nil
+	case <-ctx.Done():
+		return nil, ctx.Err()
+	case <-timer.readCancel():
+		return nil, fmt.Errorf("cassandra: read from %s timed out", t.table)
+	}
+}
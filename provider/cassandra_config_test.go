@@ -0,0 +1,57 @@
+// This Source Code Form is subject to the terms of the Mozilla Public
+// License, v. 2.0. If a copy of the MPL was not distributed with this
+// file, You can obtain one at https://mozilla.org/MPL/2.0/.
+
+package provider
+
+import (
+	"reflect"
+	"testing"
+	"time"
+
+	"github.com/gocql/gocql"
+)
+
+func TestCassandraConfigSerializedDeserialize(t *testing.T) {
+	config := CassandraConfig{
+		Hosts:    []string{"cassandra-0", "cassandra-1", "cassandra-2"},
+		Port:     9042,
+		Keyspace: "featureform",
+		Username: "featureform",
+		PasswordRef: CredentialRef{
+			Kind: "kubernetes",
+			Name: "cassandra-credentials",
+			Key:  "credential",
+		},
+		Consistency:       gocql.Quorum,
+		SerialConsistency: gocql.Serial,
+		ReplicationStrategy: CassandraReplicationStrategy{
+			Class:   "NetworkTopologyStrategy",
+			Options: map[string]string{"datacenter1": "3"},
+		},
+		RetryPolicy: CassandraRetryPolicy{
+			NumRetries: 5,
+			Backoff:    100 * time.Millisecond,
+		},
+		TLS: &TLSConfig{
+			Enabled:  true,
+			CertFile: "/etc/cassandra/cert.pem",
+			KeyFile:  "/etc/cassandra/key.pem",
+			CAFile:   "/etc/cassandra/ca.pem",
+		},
+		NumConns:       4,
+		Timeout:        30 * time.Second,
+		ConnectTimeout: 5 * time.Second,
+	}
+
+	serialized := config.Serialized()
+
+	deserialized := CassandraConfig{}
+	if err := deserialized.Deserialize(serialized); err != nil {
+		t.Fatalf("could not deserialize config: %v", err)
+	}
+
+	if !reflect.DeepEqual(config, deserialized) {
+		t.Fatalf("deserialized config did not match original.\nExpected: %#v\nGot: %#v", config, deserialized)
+	}
+}
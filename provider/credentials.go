@@ -0,0 +1,159 @@
+// This Source Code Form is subject to the terms of the Mozilla Public
+// License, v. 2.0. If a copy of the MPL was not distributed with this
+// file, You can obtain one at https://mozilla.org/MPL/2.0/.
+
+package provider
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+
+	"gopkg.in/yaml.v2"
+)
+
+// CredentialRef identifies a secret stored outside of SerializedConfig so
+// that passwords never flow through etcd metadata in plaintext. Kind names
+// where the secret lives (e.g. "env", "file", "kubernetes", "vault"); it does
+// not pick the resolver - ResolveCredential always uses the one supplied via
+// WithCredentialResolver, and Kind only appears in its error message. Name
+// and Key are resolver-specific (an env var name, a k8s Secret name plus its
+// data key, etc).
+type CredentialRef struct {
+	Kind string
+	Name string
+	Key  string
+}
+
+// CredentialResolver turns a CredentialRef into the secret value a provider
+// needs to dial its backend. Supplied via WithCredentialResolver.
+type CredentialResolver interface {
+	Resolve(ref CredentialRef) (string, error)
+}
+
+// ResolveCredential resolves ref using the CredentialResolver carried by
+// opts, erroring out instead of silently dialing with an empty secret if
+// none was supplied. Factories call this immediately before dialing so the
+// resolved value never gets written back into SerializedConfig.
+func ResolveCredential(opts providerOptions, ref CredentialRef) (string, error) {
+	if opts.credentialResolver == nil {
+		return "", fmt.Errorf("no credential resolver configured to resolve %s credential %q", ref.Kind, ref.Name)
+	}
+	return opts.credentialResolver.Resolve(ref)
+}
+
+// EnvCredentialResolver resolves a CredentialRef by reading the environment
+// variable named by ref.Name. Key is unused.
+type EnvCredentialResolver struct{}
+
+func (EnvCredentialResolver) Resolve(ref CredentialRef) (string, error) {
+	val, ok := os.LookupEnv(ref.Name)
+	if !ok {
+		return "", fmt.Errorf("env credential resolver: environment variable %q is not set", ref.Name)
+	}
+	return val, nil
+}
+
+// FileCredentialResolver resolves a CredentialRef by reading the file named
+// by ref.Name and returning its contents with trailing newlines removed
+// (other whitespace is kept). Relative names are
+// resolved against BaseDir, if set.
+type FileCredentialResolver struct {
+	BaseDir string
+}
+
+func (r FileCredentialResolver) Resolve(ref CredentialRef) (string, error) {
+	path := ref.Name
+	if r.BaseDir != "" && !filepath.IsAbs(path) {
+		path = filepath.Join(r.BaseDir, path)
+	}
+	contents, err := os.ReadFile(path)
+	if err != nil {
+		return "", fmt.Errorf("file credential resolver: %w", err)
+	}
+	return strings.TrimRight(string(contents), "\n"), nil
+}
+
+// secretGetter is the minimal surface KubernetesSecretResolver needs from a
+// Kubernetes client: the already base64-decoded data of a Secret in a
+// namespace. Kept as an interface so tests can fake it without pulling in
+// client-go.
+type secretGetter interface {
+	GetSecret(namespace, name string) (data map[string][]byte, err error)
+}
+
+// kubernetesCredential is the shape a provider's secret is yaml-marshaled
+// into before being base64-encoded under a Secret's data.credential key.
+// Operators can kubectl apply a Secret shaped like:
+//
+//	apiVersion: v1
+//	kind: Secret
+//	metadata:
+//	  name: redis-credentials
+//	data:
+//	  credential: cGFzc3dvcmQ6IGh1bnRlcjI=   # base64("password: hunter2")
+type kubernetesCredential struct {
+	Password string `yaml:"password"`
+}
+
+// KubernetesSecretResolver resolves a CredentialRef by reading a Secret
+// named ref.Name out of Namespace, yaml-unmarshaling its ref.Key entry
+// (default "credential") into a kubernetesCredential and returning Password.
+type KubernetesSecretResolver struct {
+	Namespace string
+	Secrets   secretGetter
+}
+
+func (r KubernetesSecretResolver) Resolve(ref CredentialRef) (string, error) {
+	data, err := r.Secrets.GetSecret(r.Namespace, ref.Name)
+	if err != nil {
+		return "", fmt.Errorf("kubernetes credential resolver: %w", err)
+	}
+	key := ref.Key
+	if key == "" {
+		key = "credential"
+	}
+	raw, ok := data[key]
+	if !ok {
+		return "", fmt.Errorf("kubernetes credential resolver: secret %q has no key %q", ref.Name, key)
+	}
+	var cred kubernetesCredential
+	if err := yaml.Unmarshal(raw, &cred); err != nil {
+		return "", fmt.Errorf("kubernetes credential resolver: %w", err)
+	}
+	return cred.Password, nil
+}
+
+// vaultReader is the minimal surface VaultCredentialResolver needs from a
+// Vault client, kept as an interface so tests can fake it without pulling in
+// the Vault SDK.
+type vaultReader interface {
+	Read(path string) (map[string]interface{}, error)
+}
+
+// VaultCredentialResolver resolves a CredentialRef by reading the secret at
+// path ref.Name and returning the value at ref.Key (default "password").
+type VaultCredentialResolver struct {
+	Client vaultReader
+}
+
+func (r VaultCredentialResolver) Resolve(ref CredentialRef) (string, error) {
+	data, err := r.Client.Read(ref.Name)
+	if err != nil {
+		return "", fmt.Errorf("vault credential resolver: %w", err)
+	}
+	key := ref.Key
+	if key == "" {
+		key = "password"
+	}
+	val, ok := data[key]
+	if !ok {
+		return "", fmt.Errorf("vault credential resolver: path %q has no key %q", ref.Name, key)
+	}
+	str, ok := val.(string)
+	if !ok {
+		return "", fmt.Errorf("vault credential resolver: value at %q.%q is not a string", ref.Name, key)
+	}
+	return str, nil
+}
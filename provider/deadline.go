@@ -0,0 +1,110 @@
+// This Source Code Form is subject to the terms of the Mozilla Public
+// License, v. 2.0. If a copy of the MPL was not distributed with this
+// file, You can obtain one at https://mozilla.org/MPL/2.0/.
+
+package provider
+
+import (
+	"sync"
+	"time"
+)
+
+// deadlineTimer gives a store a read/write deadline on top of a backend
+// client that doesn't natively support one, mirroring the cancel-channel
+// pattern net.Pipe uses internally. A blocking call selects on the channel
+// deadlineTimer hands back alongside whatever it's actually waiting on, so
+// a deadline that fires unblocks it without needing to touch the
+// underlying connection.
+type deadlineTimer struct {
+	mu            sync.Mutex
+	readCancelCh  chan struct{}
+	writeCancelCh chan struct{}
+	readTimer     *time.Timer
+	writeTimer    *time.Timer
+}
+
+func newDeadlineTimer() *deadlineTimer {
+	return &deadlineTimer{
+		readCancelCh:  make(chan struct{}),
+		writeCancelCh: make(chan struct{}),
+	}
+}
+
+// readCancel returns the channel that closes when the read deadline passes.
+func (d *deadlineTimer) readCancel() <-chan struct{} {
+	d.mu.Lock()
+	defer d.mu.Unlock()
+	return d.readCancelCh
+}
+
+// writeCancel returns the channel that closes when the write deadline passes.
+func (d *deadlineTimer) writeCancel() <-chan struct{} {
+	d.mu.Lock()
+	defer d.mu.Unlock()
+	return d.writeCancelCh
+}
+
+// SetReadDeadline arms (or disables, for a zero time.Time) the deadline that
+// closes readCancel's channel. A time already in the past closes it
+// immediately.
+func (d *deadlineTimer) SetReadDeadline(t time.Time) error {
+	d.mu.Lock()
+	defer d.mu.Unlock()
+	setDeadlineLocked(&d.readTimer, &d.readCancelCh, t)
+	return nil
+}
+
+// SetWriteDeadline arms (or disables, for a zero time.Time) the deadline
+// that closes writeCancel's channel. A time already in the past closes it
+// immediately.
+func (d *deadlineTimer) SetWriteDeadline(t time.Time) error {
+	d.mu.Lock()
+	defer d.mu.Unlock()
+	setDeadlineLocked(&d.writeTimer, &d.writeCancelCh, t)
+	return nil
+}
+
+// setDeadlineLocked stops any timer pending on *timer, replaces *cancelCh if
+// the old one already fired, and - unless t is the zero value - arms a new
+// timer that closes the (possibly new) channel when t arrives. Mirrors
+// net.pipeDeadline.set: a failed Stop means the callback may still be
+// in-flight, so it blocks on *cancelCh rather than racing it - two callers
+// closing the same channel panics. Must be called with d.mu held.
+func setDeadlineLocked(timer **time.Timer, cancelCh *chan struct{}, t time.Time) {
+	if *timer != nil && !(*timer).Stop() {
+		<-*cancelCh // wait for the in-flight callback to finish closing it
+	}
+	*timer = nil
+
+	closed := isClosedCh(*cancelCh)
+	if t.IsZero() {
+		if closed {
+			*cancelCh = make(chan struct{})
+		}
+		return
+	}
+
+	if d := time.Until(t); d > 0 {
+		if closed {
+			*cancelCh = make(chan struct{})
+		}
+		ch := *cancelCh
+		*timer = time.AfterFunc(d, func() {
+			close(ch)
+		})
+		return
+	}
+
+	if !closed {
+		close(*cancelCh)
+	}
+}
+
+func isClosedCh(ch chan struct{}) bool {
+	select {
+	case <-ch:
+		return true
+	default:
+		return false
+	}
+}
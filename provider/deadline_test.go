@@ -0,0 +1,51 @@
+// This Source Code Form is subject to the terms of the Mozilla Public
+// License, v. 2.0. If a copy of the MPL was not distributed with this
+// file, You can obtain one at https://mozilla.org/MPL/2.0/.
+
+package provider
+
+import (
+	"testing"
+	"time"
+)
+
+// TestDeadlineTimerRepeatedSetDoesNotPanic sets a near-immediate deadline in
+// a tight loop, so a SetReadDeadline call is virtually guaranteed to race
+// the previous call's timer callback. A setDeadlineLocked that doesn't wait
+// for a failed Stop's callback to finish panics with "close of closed
+// channel" within the first handful of iterations. Run with -race.
+func TestDeadlineTimerRepeatedSetDoesNotPanic(t *testing.T) {
+	d := newDeadlineTimer()
+	for i := 0; i < 1000; i++ {
+		if err := d.SetReadDeadline(time.Now().Add(time.Microsecond)); err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+	}
+}
+
+func TestDeadlineTimerReadCancelFiresAfterDeadline(t *testing.T) {
+	d := newDeadlineTimer()
+	if err := d.SetReadDeadline(time.Now().Add(10 * time.Millisecond)); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	select {
+	case <-d.readCancel():
+	case <-time.After(time.Second):
+		t.Fatalf("timed out waiting for read deadline to fire")
+	}
+}
+
+func TestDeadlineTimerZeroDisablesDeadline(t *testing.T) {
+	d := newDeadlineTimer()
+	if err := d.SetWriteDeadline(time.Now().Add(5 * time.Millisecond)); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if err := d.SetWriteDeadline(time.Time{}); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	select {
+	case <-d.writeCancel():
+		t.Fatalf("write deadline fired after being disabled")
+	case <-time.After(20 * time.Millisecond):
+	}
+}
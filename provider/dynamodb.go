@@ -0,0 +1,77 @@
+// This Source Code Form is subject to the terms of the Mozilla Public
+// License, v. 2.0. If a copy of the MPL was not distributed with this
+// file, You can obtain one at https://mozilla.org/MPL/2.0/.
+
+package provider
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/aws/aws-sdk-go/aws"
+	"github.com/aws/aws-sdk-go/aws/credentials"
+	"github.com/aws/aws-sdk-go/aws/session"
+	"github.com/aws/aws-sdk-go/service/dynamodb"
+)
+
+type dynamodbOnlineStore struct {
+	BaseProvider
+	client *dynamodb.DynamoDB
+}
+
+func dynamodbOnlineStoreFactory(opts providerOptions) (Provider, error) {
+	config := DynamodbConfig{}
+	if err := config.Deserialize(opts.Config()); err != nil {
+		return nil, fmt.Errorf("dynamodb: could not deserialize config: %w", err)
+	}
+
+	secretKey, err := resolveDynamodbSecret(opts, config)
+	if err != nil {
+		return nil, err
+	}
+
+	awsConfig := aws.NewConfig().WithRegion(config.Region)
+	if config.Addr != "" {
+		awsConfig = awsConfig.WithEndpoint(config.Addr)
+	}
+	if config.AccessKeyID != "" {
+		awsConfig = awsConfig.WithCredentials(credentials.NewStaticCredentials(config.AccessKeyID, secretKey, ""))
+	}
+
+	sess, err := session.NewSession(awsConfig)
+	if err != nil {
+		opts.Logger().Errorw("dynamodb: could not create session", "region", config.Region, "err", err)
+		return nil, fmt.Errorf("dynamodb: could not create session: %w", err)
+	}
+
+	return &dynamodbOnlineStore{
+		BaseProvider: NewBaseProvider(DynamoDBOnline, opts),
+		client:       dynamodb.New(sess),
+	}, nil
+}
+
+// resolveDynamodbSecret resolves config.SecretRef the same way
+// cassandraOnlineStoreFactory resolves its PasswordRef, so the secret
+// access key never needs to sit in DynamodbConfig's SerializedConfig. An
+// unset ref means the instance relies on ambient credentials (an IAM
+// instance role, say) instead of a static key pair.
+func resolveDynamodbSecret(opts providerOptions, config DynamodbConfig) (string, error) {
+	if config.SecretRef.Name == "" {
+		return "", nil
+	}
+	return ResolveCredential(opts, config.SecretRef)
+}
+
+func (store *dynamodbOnlineStore) Close() error {
+	return nil
+}
+
+func (store *dynamodbOnlineStore) HealthCheck(ctx context.Context) error {
+	_, err := store.client.ListTablesWithContext(ctx, &dynamodb.ListTablesInput{Limit: aws.Int64(1)})
+	if err != nil {
+		store.Metrics.Counter("dynamodb.health_check.failure").Inc()
+		return err
+	}
+	store.Metrics.Counter("dynamodb.health_check.success").Inc()
+	return nil
+}
@@ -5,21 +5,23 @@
 package provider
 
 import (
+	"context"
 	"encoding/json"
 	"fmt"
+	"time"
 
 	"github.com/gocql/gocql"
+	"go.uber.org/zap"
+
+	"github.com/featureform/metrics"
 )
 
 func init() {
 	unregisteredFactories := map[Type]Factory{
 		LocalOnline:      localOnlineStoreFactory,
 		RedisOnline:      redisOnlineStoreFactory,
-<<<<<<< HEAD:provider/provider.go
 		CassandraOnline:  cassandraOnlineStoreFactory,
-=======
 		DynamoDBOnline:   dynamodbOnlineStoreFactory,
->>>>>>> e8846b1 (Passed all Dyanamodb tests):ff/serving/provider/provider.go
 		MemoryOffline:    memoryOfflineStoreFactory,
 		PostgresOffline:  postgresOfflineStoreFactory,
 		SnowflakeOffline: snowflakeOfflineStoreFactory,
@@ -37,16 +39,18 @@ type SerializedConfig []byte
 type SerializedTableSchema []byte
 
 type RedisConfig struct {
-	Prefix   string
-	Addr     string
-	Password string
-	DB       int
+	Prefix      string
+	Addr        string
+	PasswordRef CredentialRef
+	DB          int
 }
 
 type DynamodbConfig struct {
-	Prefix string
-	Addr   string
-	Region string
+	Prefix      string
+	Addr        string
+	Region      string
+	AccessKeyID string
+	SecretRef   CredentialRef
 }
 
 func (r RedisConfig) Serialized() SerializedConfig {
@@ -65,11 +69,48 @@ func (r *RedisConfig) Deserialize(config SerializedConfig) error {
 	return nil
 }
 
+// TLSConfig configures transport security for a provider's connection to
+// its backend. A nil *TLSConfig (or one with Enabled false) means plaintext.
+type TLSConfig struct {
+	Enabled            bool
+	CertFile           string
+	KeyFile            string
+	CAFile             string
+	InsecureSkipVerify bool
+}
+
+// CassandraReplicationStrategy is the keyspace replication strategy used
+// when auto-creating the keyspace, e.g. {Class: "NetworkTopologyStrategy",
+// Options: map[string]string{"datacenter1": "3"}}.
+type CassandraReplicationStrategy struct {
+	Class   string
+	Options map[string]string
+}
+
+// CassandraRetryPolicy configures gocql's exponential backoff retry policy.
+type CassandraRetryPolicy struct {
+	NumRetries int
+	Backoff    time.Duration
+}
+
+// CassandraConfig describes a (possibly multi-node) Cassandra cluster. The
+// live *gocql.Session is deliberately not part of this struct - it isn't
+// serializable and doesn't belong in etcd metadata - and is instead held
+// privately by cassandraOnlineStore once the factory dials it.
 type CassandraConfig struct {
-	keyspace    string
-	Addr        string
-	session     *gocql.Session
-	Consistency gocql.Consistency
+	Hosts               []string
+	Port                int
+	Keyspace            string
+	Username            string
+	PasswordRef         CredentialRef
+	Consistency         gocql.Consistency
+	SerialConsistency   gocql.SerialConsistency
+	ReplicationStrategy CassandraReplicationStrategy
+	RetryPolicy         CassandraRetryPolicy
+	TLS                 *TLSConfig
+	NumConns            int
+	Timeout             time.Duration
+	ConnectTimeout      time.Duration
 }
 
 func (r CassandraConfig) Serialized() SerializedConfig {
@@ -109,11 +150,24 @@ type Provider interface {
 	AsOfflineStore() (OfflineStore, error)
 	Type() Type
 	Config() SerializedConfig
+	// Close releases whatever connection the provider opened to its
+	// backend. Safe to call more than once.
+	Close() error
+	// HealthCheck reports whether the provider's backend is currently
+	// reachable, honoring ctx's deadline.
+	HealthCheck(ctx context.Context) error
 }
 
+// BaseProvider holds the state every provider gets for free once it is
+// constructed through Get: the resolved config plus the cross-cutting
+// dependencies (logger, metrics sink) that were either passed in via
+// options or defaulted. Concrete providers embed BaseProvider instead of
+// wiring a logger or metrics client themselves.
 type BaseProvider struct {
 	ProviderType   Type
 	ProviderConfig SerializedConfig
+	Logger         *zap.SugaredLogger
+	Metrics        metrics.Sink
 }
 
 func (provider BaseProvider) AsOnlineStore() (OnlineStore, error) {
@@ -132,10 +186,107 @@ func (provider BaseProvider) Config() SerializedConfig {
 	return provider.ProviderConfig
 }
 
-type Factory func(SerializedConfig) (Provider, error)
+// Close is a no-op default for providers that don't hold an open
+// connection (e.g. MemoryOffline). Providers that do should shadow it.
+func (provider BaseProvider) Close() error {
+	return nil
+}
+
+// HealthCheck is a no-op default that always reports healthy. Providers
+// backed by an actual connection should shadow it with a real check.
+func (provider BaseProvider) HealthCheck(ctx context.Context) error {
+	return nil
+}
+
+// NewBaseProvider builds the BaseProvider factories should embed, carrying
+// over the config and cross-cutting dependencies resolved from the Options
+// passed to Get.
+func NewBaseProvider(t Type, opts providerOptions) BaseProvider {
+	return BaseProvider{
+		ProviderType:   t,
+		ProviderConfig: opts.config,
+		Logger:         opts.logger,
+		Metrics:        opts.metrics,
+	}
+}
+
+// Factory builds a Provider from the options resolved by Get. Use
+// opts.Config()/opts.Context()/etc to read the individual settings, or pass
+// opts straight through to NewBaseProvider.
+type Factory func(opts providerOptions) (Provider, error)
 
 type Type string
 
+// providerOptions is the resolved set of cross-cutting concerns a Factory is
+// handed. It is assembled by Get from the defaults plus whatever Options the
+// caller supplied, so factories never see a partially-populated value.
+type providerOptions struct {
+	config             SerializedConfig
+	logger             *zap.SugaredLogger
+	metrics            metrics.Sink
+	credentialResolver CredentialResolver
+	ctx                context.Context
+}
+
+func (opts providerOptions) Config() SerializedConfig               { return opts.config }
+func (opts providerOptions) Logger() *zap.SugaredLogger             { return opts.logger }
+func (opts providerOptions) Metrics() metrics.Sink                  { return opts.metrics }
+func (opts providerOptions) CredentialResolver() CredentialResolver { return opts.credentialResolver }
+func (opts providerOptions) Context() context.Context               { return opts.ctx }
+
+func defaultProviderOptions() providerOptions {
+	return providerOptions{
+		logger:  zap.NewNop().Sugar(),
+		metrics: metrics.Nop,
+		ctx:     context.Background(),
+	}
+}
+
+// Option configures a cross-cutting concern of a provider at construction
+// time. Options are applied in order on top of the defaults, so a later
+// Option overrides an earlier one of the same kind.
+type Option func(*providerOptions)
+
+// WithConfig sets the serialized, provider-specific config (host, port,
+// credentials reference, etc). Every provider needs one of these.
+func WithConfig(config SerializedConfig) Option {
+	return func(opts *providerOptions) {
+		opts.config = config
+	}
+}
+
+// WithLogger overrides the logger a provider uses for structured logging.
+// Defaults to a no-op logger.
+func WithLogger(logger *zap.SugaredLogger) Option {
+	return func(opts *providerOptions) {
+		opts.logger = logger
+	}
+}
+
+// WithMetrics overrides the metrics.Sink a provider uses to emit counters
+// and gauges. Defaults to metrics.Nop.
+func WithMetrics(sink metrics.Sink) Option {
+	return func(opts *providerOptions) {
+		opts.metrics = sink
+	}
+}
+
+// WithCredentialResolver supplies the CredentialResolver a provider uses to
+// turn a CredentialRef embedded in its config into an actual secret.
+func WithCredentialResolver(resolver CredentialResolver) Option {
+	return func(opts *providerOptions) {
+		opts.credentialResolver = resolver
+	}
+}
+
+// WithContext sets the context a provider's construction (and any
+// construction-time dialing) runs under. Defaults to context.Background().
+func WithContext(ctx context.Context) Option {
+	return func(opts *providerOptions) {
+		opts.ctx = ctx
+	}
+}
+
 var factories map[Type]Factory = make(map[Type]Factory)
 
 func RegisterFactory(t Type, f Factory) error {
@@ -146,10 +297,18 @@ func RegisterFactory(t Type, f Factory) error {
 	return nil
 }
 
-func Get(t Type, config SerializedConfig) (Provider, error) {
+// Get builds the Provider registered for t, applying opts on top of the
+// default cross-cutting concerns (no-op logger, no-op metrics sink,
+// background context). At minimum callers should pass WithConfig; a
+// provider built without one gets an empty SerializedConfig.
+func Get(t Type, opts ...Option) (Provider, error) {
 	f, has := factories[t]
 	if !has {
 		return nil, fmt.Errorf("no provider of type: %s", t)
 	}
-	return f(config)
+	resolved := defaultProviderOptions()
+	for _, opt := range opts {
+		opt(&resolved)
+	}
+	return f(resolved)
 }
@@ -0,0 +1,69 @@
+// This Source Code Form is subject to the terms of the Mozilla Public
+// License, v. 2.0. If a copy of the MPL was not distributed with this
+// file, You can obtain one at https://mozilla.org/MPL/2.0/.
+
+package provider
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/go-redis/redis/v8"
+)
+
+type redisOnlineStore struct {
+	BaseProvider
+	client *redis.Client
+}
+
+func redisOnlineStoreFactory(opts providerOptions) (Provider, error) {
+	config := RedisConfig{}
+	if err := config.Deserialize(opts.Config()); err != nil {
+		return nil, fmt.Errorf("redis: could not deserialize config: %w", err)
+	}
+
+	password, err := resolveRedisPassword(opts, config)
+	if err != nil {
+		return nil, err
+	}
+
+	client := redis.NewClient(&redis.Options{
+		Addr:     config.Addr,
+		Password: password,
+		DB:       config.DB,
+	})
+	if err := client.Ping(opts.Context()).Err(); err != nil {
+		opts.Logger().Errorw("redis: could not connect", "addr", config.Addr, "err", err)
+		return nil, fmt.Errorf("redis: could not connect: %w", err)
+	}
+
+	return &redisOnlineStore{
+		BaseProvider: NewBaseProvider(RedisOnline, opts),
+		client:       client,
+	}, nil
+}
+
+// resolveRedisPassword resolves config.PasswordRef the same way
+// cassandraOnlineStoreFactory resolves its own PasswordRef, so a plaintext
+// password never needs to sit in RedisConfig's SerializedConfig. An unset
+// ref means the instance has no password (e.g. local dev).
+func resolveRedisPassword(opts providerOptions, config RedisConfig) (string, error) {
+	if config.PasswordRef.Name == "" {
+		return "", nil
+	}
+	return ResolveCredential(opts, config.PasswordRef)
+}
+
+func (store *redisOnlineStore) Close() error {
+	return store.client.Close()
+}
+
+func (store *redisOnlineStore) HealthCheck(ctx context.Context) error {
+	err := store.client.Ping(ctx).Err()
+	if err != nil {
+		store.Metrics.Counter("redis.health_check.failure").Inc()
+		return err
+	}
+	store.Metrics.Counter("redis.health_check.success").Inc()
+	return nil
+}
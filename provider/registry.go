@@ -0,0 +1,226 @@
+// This Source Code Form is subject to the terms of the Mozilla Public
+// License, v. 2.0. If a copy of the MPL was not distributed with this
+// file, You can obtain one at https://mozilla.org/MPL/2.0/.
+
+package provider
+
+import (
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"fmt"
+	"sync"
+	"time"
+)
+
+// HealthStatus is a point-in-time verdict from a Registry's background
+// health check loop, published on the channel returned by Statuses.
+type HealthStatus struct {
+	Type    Type
+	Healthy bool
+	Err     error
+}
+
+type registryKey struct {
+	t          Type
+	configHash string
+}
+
+type registryEntry struct {
+	provider Provider
+	refs     int
+}
+
+// Registry tracks providers constructed through its Get, deduplicating
+// identical (Type, config) instantiations with reference counting so the
+// metadata server and job runner can share a long-lived Redis/Cassandra/
+// DynamoDB connection instead of dialing a fresh one per job. A background
+// loop health-checks every live provider and publishes each result on
+// Statuses, so serving can fail fast when a backend goes unreachable. Every
+// round publishes a status per provider, whether or not its health changed
+// since the previous round.
+type Registry struct {
+	mu       sync.Mutex
+	entries  map[registryKey]*registryEntry
+	statusCh chan HealthStatus
+	interval time.Duration
+	stopCh   chan struct{}
+	stopOnce sync.Once
+	closed   bool
+	// healthRounds tracks in-flight runHealthChecks rounds, so CloseAll can
+	// wait for one already underway to finish before it closes the very
+	// providers that round is concurrently calling HealthCheck on.
+	healthRounds sync.WaitGroup
+}
+
+// NewRegistry builds a Registry whose background loop health-checks every
+// live provider every interval. An interval of zero disables the loop;
+// Get/Release/CloseAll still work without it.
+func NewRegistry(interval time.Duration) *Registry {
+	r := &Registry{
+		entries:  make(map[registryKey]*registryEntry),
+		statusCh: make(chan HealthStatus, 16),
+		interval: interval,
+		stopCh:   make(chan struct{}),
+	}
+	if interval > 0 {
+		go r.healthCheckLoop()
+	}
+	return r
+}
+
+// Statuses returns the channel health check results are published on, one
+// per provider per round. The channel buffers 16 statuses and sends are
+// non-blocking: once the buffer is full, a status is dropped rather than
+// stalling the health check loop.
+func (r *Registry) Statuses() <-chan HealthStatus {
+	return r.statusCh
+}
+
+func configHash(config SerializedConfig) string {
+	sum := sha256.Sum256(config)
+	return hex.EncodeToString(sum[:])
+}
+
+// Get returns the provider registered for (t, opts), constructing and
+// caching it on the first call and incrementing a reference count on every
+// later call with the same (t, config). Each Get should be paired with a
+// Release once the caller is done with the provider. Get fails once
+// CloseAll has been called: a registry that's shutting down shouldn't hand
+// out a connection it will neither health-check nor close.
+func (r *Registry) Get(t Type, opts ...Option) (Provider, error) {
+	resolved := defaultProviderOptions()
+	for _, opt := range opts {
+		opt(&resolved)
+	}
+	key := registryKey{t: t, configHash: configHash(resolved.config)}
+
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	if r.closed {
+		return nil, fmt.Errorf("registry is closed")
+	}
+	if entry, ok := r.entries[key]; ok {
+		entry.refs++
+		return entry.provider, nil
+	}
+
+	p, err := Get(t, opts...)
+	if err != nil {
+		return nil, err
+	}
+	r.entries[key] = &registryEntry{provider: p, refs: 1}
+	return p, nil
+}
+
+// Release decrements (t, config)'s reference count, closing the underlying
+// provider once no caller still holds it. A (t, config) the registry never
+// served, or has already fully released, is a no-op.
+func (r *Registry) Release(t Type, config SerializedConfig) error {
+	key := registryKey{t: t, configHash: configHash(config)}
+
+	r.mu.Lock()
+	entry, ok := r.entries[key]
+	if !ok {
+		r.mu.Unlock()
+		return nil
+	}
+	entry.refs--
+	if entry.refs > 0 {
+		r.mu.Unlock()
+		return nil
+	}
+	delete(r.entries, key)
+	r.mu.Unlock()
+
+	return entry.provider.Close()
+}
+
+// CloseAll closes every provider the registry currently holds, regardless
+// of outstanding reference counts, and stops the health check loop. It waits
+// for any runHealthChecks round already underway to finish first, so a
+// provider is never Closed while that same round still has a HealthCheck
+// call in flight against it. Safe to call more than once.
+func (r *Registry) CloseAll() error {
+	r.stopOnce.Do(func() { close(r.stopCh) })
+
+	r.mu.Lock()
+	r.closed = true
+	r.mu.Unlock()
+
+	r.healthRounds.Wait()
+
+	r.mu.Lock()
+	entries := r.entries
+	r.entries = make(map[registryKey]*registryEntry)
+	r.mu.Unlock()
+
+	var firstErr error
+	for _, entry := range entries {
+		if err := entry.provider.Close(); err != nil && firstErr == nil {
+			firstErr = err
+		}
+	}
+	return firstErr
+}
+
+func (r *Registry) healthCheckLoop() {
+	ticker := time.NewTicker(r.interval)
+	defer ticker.Stop()
+	for {
+		select {
+		case <-r.stopCh:
+			return
+		case <-ticker.C:
+			if !r.beginHealthRound() {
+				return
+			}
+			r.runHealthChecks()
+			r.healthRounds.Done()
+		}
+	}
+}
+
+// beginHealthRound registers a new runHealthChecks round with healthRounds,
+// unless CloseAll has already set closed - checked and incremented under the
+// same lock CloseAll sets closed under, so a round can never start after
+// CloseAll has already moved on to waiting for (and thus believing there are
+// no more) in-flight rounds.
+func (r *Registry) beginHealthRound() bool {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	if r.closed {
+		return false
+	}
+	r.healthRounds.Add(1)
+	return true
+}
+
+// runHealthChecks checks every live provider concurrently, so one slow or
+// hung backend can't delay the status of the rest until its own timeout
+// expires.
+func (r *Registry) runHealthChecks() {
+	r.mu.Lock()
+	snapshot := make(map[registryKey]Provider, len(r.entries))
+	for key, entry := range r.entries {
+		snapshot[key] = entry.provider
+	}
+	r.mu.Unlock()
+
+	var wg sync.WaitGroup
+	for key, p := range snapshot {
+		wg.Add(1)
+		go func(key registryKey, p Provider) {
+			defer wg.Done()
+			ctx, cancel := context.WithTimeout(context.Background(), r.interval)
+			err := p.HealthCheck(ctx)
+			cancel()
+			status := HealthStatus{Type: key.t, Healthy: err == nil, Err: err}
+			select {
+			case r.statusCh <- status:
+			default:
+			}
+		}(key, p)
+	}
+	wg.Wait()
+}
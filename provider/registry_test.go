@@ -0,0 +1,251 @@
+// This Source Code Form is subject to the terms of the Mozilla Public
+// License, v. 2.0. If a copy of the MPL was not distributed with this
+// file, You can obtain one at https://mozilla.org/MPL/2.0/.
+
+package provider
+
+import (
+	"context"
+	"fmt"
+	"sync"
+	"testing"
+	"time"
+)
+
+// mockRegistryProvider extends the MockOfflineCreateTransformationFail
+// pattern used in runner's tests with the Close/HealthCheck behavior the
+// registry depends on.
+type mockRegistryProvider struct {
+	BaseProvider
+	closeErr        error
+	healthCheckErr  error
+	healthCheckHold time.Duration
+
+	mu              sync.Mutex
+	closed          int
+	inHealthCheck   bool
+	overlapDetected bool
+}
+
+func (m *mockRegistryProvider) Close() error {
+	m.mu.Lock()
+	if m.inHealthCheck {
+		m.overlapDetected = true
+	}
+	m.closed++
+	m.mu.Unlock()
+	return m.closeErr
+}
+
+func (m *mockRegistryProvider) HealthCheck(ctx context.Context) error {
+	m.mu.Lock()
+	m.inHealthCheck = true
+	m.mu.Unlock()
+
+	if m.healthCheckHold > 0 {
+		time.Sleep(m.healthCheckHold)
+	}
+
+	m.mu.Lock()
+	m.inHealthCheck = false
+	m.mu.Unlock()
+	return m.healthCheckErr
+}
+
+const mockRegistryType Type = "MOCK_REGISTRY"
+
+func registerMockRegistryFactory(t *testing.T, build func(opts providerOptions) *mockRegistryProvider) {
+	t.Helper()
+	if err := RegisterFactory(mockRegistryType, func(opts providerOptions) (Provider, error) {
+		return build(opts), nil
+	}); err != nil {
+		t.Fatalf("could not register mock registry factory: %v", err)
+	}
+	t.Cleanup(func() {
+		delete(factories, mockRegistryType)
+	})
+}
+
+func TestRegistryGetDeduplicatesByConfig(t *testing.T) {
+	created := 0
+	registerMockRegistryFactory(t, func(opts providerOptions) *mockRegistryProvider {
+		created++
+		return &mockRegistryProvider{BaseProvider: NewBaseProvider(mockRegistryType, opts)}
+	})
+
+	registry := NewRegistry(0)
+	defer registry.CloseAll()
+
+	configA := WithConfig(SerializedConfig("a"))
+	configB := WithConfig(SerializedConfig("b"))
+
+	first, err := registry.Get(mockRegistryType, configA)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	second, err := registry.Get(mockRegistryType, configA)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if first != second {
+		t.Fatalf("expected Get with the same config to return the cached provider")
+	}
+	if _, err := registry.Get(mockRegistryType, configB); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if created != 2 {
+		t.Fatalf("expected 2 providers to be created (one per distinct config), got %d", created)
+	}
+}
+
+func TestRegistryReleaseClosesOnLastRef(t *testing.T) {
+	var instance *mockRegistryProvider
+	registerMockRegistryFactory(t, func(opts providerOptions) *mockRegistryProvider {
+		instance = &mockRegistryProvider{BaseProvider: NewBaseProvider(mockRegistryType, opts)}
+		return instance
+	})
+
+	registry := NewRegistry(0)
+	defer registry.CloseAll()
+
+	config := SerializedConfig("a")
+	if _, err := registry.Get(mockRegistryType, WithConfig(config)); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if _, err := registry.Get(mockRegistryType, WithConfig(config)); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	if err := registry.Release(mockRegistryType, config); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if instance.closed != 0 {
+		t.Fatalf("expected provider to stay open while a ref remains, closed=%d", instance.closed)
+	}
+
+	if err := registry.Release(mockRegistryType, config); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if instance.closed != 1 {
+		t.Fatalf("expected provider to close once refs reach zero, closed=%d", instance.closed)
+	}
+}
+
+func TestRegistryCloseAllClosesEverything(t *testing.T) {
+	var mu sync.Mutex
+	var instances []*mockRegistryProvider
+	registerMockRegistryFactory(t, func(opts providerOptions) *mockRegistryProvider {
+		p := &mockRegistryProvider{BaseProvider: NewBaseProvider(mockRegistryType, opts)}
+		mu.Lock()
+		instances = append(instances, p)
+		mu.Unlock()
+		return p
+	})
+
+	registry := NewRegistry(0)
+	for i := 0; i < 3; i++ {
+		config := SerializedConfig(fmt.Sprintf("config-%d", i))
+		if _, err := registry.Get(mockRegistryType, WithConfig(config)); err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+	}
+
+	if err := registry.CloseAll(); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	for i, instance := range instances {
+		if instance.closed != 1 {
+			t.Fatalf("expected instance %d to be closed exactly once, got %d", i, instance.closed)
+		}
+	}
+}
+
+func TestRegistryHealthCheckLoopReportsStatus(t *testing.T) {
+	registerMockRegistryFactory(t, func(opts providerOptions) *mockRegistryProvider {
+		return &mockRegistryProvider{
+			BaseProvider:   NewBaseProvider(mockRegistryType, opts),
+			healthCheckErr: fmt.Errorf("backend unreachable"),
+		}
+	})
+
+	registry := NewRegistry(5 * time.Millisecond)
+	defer registry.CloseAll()
+
+	if _, err := registry.Get(mockRegistryType, WithConfig(SerializedConfig("a"))); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	select {
+	case status := <-registry.Statuses():
+		if status.Healthy {
+			t.Fatalf("expected an unhealthy status, got healthy")
+		}
+	case <-time.After(time.Second):
+		t.Fatalf("timed out waiting for a health status")
+	}
+}
+
+// TestRegistryCloseAllWaitsForInFlightHealthCheck uses a nonzero interval so
+// the health check loop actually runs, holds HealthCheck open long enough
+// that CloseAll is certain to be called mid-round, and fails if Close ever
+// overlaps that in-flight HealthCheck call on the same provider. Run with
+// -race: a bug here would otherwise also be a same-field read/write race.
+func TestRegistryCloseAllWaitsForInFlightHealthCheck(t *testing.T) {
+	var instance *mockRegistryProvider
+	registerMockRegistryFactory(t, func(opts providerOptions) *mockRegistryProvider {
+		instance = &mockRegistryProvider{
+			BaseProvider:    NewBaseProvider(mockRegistryType, opts),
+			healthCheckHold: 50 * time.Millisecond,
+		}
+		return instance
+	})
+
+	registry := NewRegistry(5 * time.Millisecond)
+	if _, err := registry.Get(mockRegistryType, WithConfig(SerializedConfig("a"))); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	// Give the loop a chance to start a round before racing CloseAll against
+	// it; healthCheckHold keeps that round in flight well past this point.
+	time.Sleep(10 * time.Millisecond)
+
+	if err := registry.CloseAll(); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	instance.mu.Lock()
+	defer instance.mu.Unlock()
+	if instance.overlapDetected {
+		t.Fatalf("Close ran while a HealthCheck call was still in flight")
+	}
+}
+
+// TestRegistryConcurrentGetAndCloseAll exercises Get and CloseAll from many
+// goroutines at once; run with -race to catch data races on the entry map.
+func TestRegistryConcurrentGetAndCloseAll(t *testing.T) {
+	registerMockRegistryFactory(t, func(opts providerOptions) *mockRegistryProvider {
+		return &mockRegistryProvider{BaseProvider: NewBaseProvider(mockRegistryType, opts)}
+	})
+
+	registry := NewRegistry(0)
+
+	var wg sync.WaitGroup
+	for i := 0; i < 20; i++ {
+		wg.Add(1)
+		go func(i int) {
+			defer wg.Done()
+			config := SerializedConfig(fmt.Sprintf("config-%d", i%4))
+			if _, err := registry.Get(mockRegistryType, WithConfig(config)); err != nil && err.Error() != "registry is closed" {
+				t.Errorf("unexpected error: %v", err)
+			}
+		}(i)
+	}
+	for i := 0; i < 5; i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			registry.CloseAll()
+		}()
+	}
+	wg.Wait()
+}
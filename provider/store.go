@@ -0,0 +1,147 @@
+// This Source Code Form is subject to the terms of the Mozilla Public
+// License, v. 2.0. If a copy of the MPL was not distributed with this
+// file, You can obtain one at https://mozilla.org/MPL/2.0/.
+
+package provider
+
+import "context"
+
+// ValueType names the scalar types an OnlineStore can serve.
+type ValueType string
+
+const (
+	String ValueType = "string"
+	Int    ValueType = "int"
+	Float  ValueType = "float"
+	Bool   ValueType = "bool"
+)
+
+// OfflineResourceType distinguishes the kind of resource a ResourceID
+// refers to.
+type OfflineResourceType int
+
+const (
+	Feature OfflineResourceType = iota
+	Label
+)
+
+type ResourceID struct {
+	Name    string
+	Variant string
+	Type    OfflineResourceType
+}
+
+type MaterializationID string
+
+type TableSchema struct {
+	Columns []string
+}
+
+type ResourceSchema struct {
+	Entity string
+	Value  string
+	TS     string
+}
+
+type SourceMapping struct {
+	Template string
+	Source   string
+}
+
+type TransformationType int
+
+const (
+	SQLTransformation TransformationType = iota
+)
+
+type TransformationConfig struct {
+	Type          TransformationType
+	TargetTableID ResourceID
+	Query         string
+	SourceMapping []SourceMapping
+}
+
+type TrainingSetDef struct {
+	ID       ResourceID
+	Label    ResourceID
+	Features []ResourceID
+}
+
+// OfflineTable is a single resource's backing table in an OfflineStore.
+type OfflineTable interface {
+	Write(ctx context.Context, schema TableSchema) error
+}
+
+// PrimaryTable is the raw source table a set of resources is registered
+// against.
+type PrimaryTable interface {
+	OfflineTable
+}
+
+// TransformationTable is the output table of a batch transformation.
+type TransformationTable interface {
+	OfflineTable
+}
+
+// FeatureIterator walks the rows of a materialized feature.
+type FeatureIterator interface {
+	Next(ctx context.Context) bool
+	Value() interface{}
+	Err() error
+}
+
+// Materialization is a point-in-time snapshot of a feature, precomputed so
+// it can be served without recomputing the underlying transformation.
+type Materialization interface {
+	ID() MaterializationID
+	NumRows(ctx context.Context) (int64, error)
+	IterateSegment(ctx context.Context, begin, end int64) (FeatureIterator, error)
+}
+
+// TrainingSetIterator walks the rows of a materialized training set.
+type TrainingSetIterator interface {
+	Next(ctx context.Context) bool
+	Features() []interface{}
+	Label() interface{}
+	Err() error
+}
+
+// OfflineStore is the provider-agnostic interface for backends that build
+// and serve materializations and training sets out of batch
+// transformations. Every method takes a ctx so a caller - the runner
+// driving a job, or a request against serving - can cancel or bound a
+// potentially long-running call.
+type OfflineStore interface {
+	Provider
+	CreateResourceTable(ctx context.Context, id ResourceID, schema TableSchema) (OfflineTable, error)
+	GetResourceTable(ctx context.Context, id ResourceID) (OfflineTable, error)
+	CreateMaterialization(ctx context.Context, id ResourceID) (Materialization, error)
+	GetMaterialization(ctx context.Context, id MaterializationID) (Materialization, error)
+	UpdateMaterialization(ctx context.Context, id ResourceID) (Materialization, error)
+	DeleteMaterialization(ctx context.Context, id MaterializationID) error
+	CreateTrainingSet(ctx context.Context, def TrainingSetDef) error
+	UpdateTrainingSet(ctx context.Context, def TrainingSetDef) error
+	GetTrainingSet(ctx context.Context, id ResourceID) (TrainingSetIterator, error)
+	CreatePrimaryTable(ctx context.Context, id ResourceID, schema TableSchema) (PrimaryTable, error)
+	GetPrimaryTable(ctx context.Context, id ResourceID) (PrimaryTable, error)
+	RegisterResourceFromSourceTable(ctx context.Context, id ResourceID, schema ResourceSchema) (OfflineTable, error)
+	RegisterPrimaryFromSourceTable(ctx context.Context, id ResourceID, sourceName string) (PrimaryTable, error)
+	CreateTransformation(ctx context.Context, config TransformationConfig) error
+	UpdateTransformation(ctx context.Context, config TransformationConfig) error
+	GetTransformationTable(ctx context.Context, id ResourceID) (TransformationTable, error)
+}
+
+// OnlineStoreTable is a single feature/variant's key-value table in an
+// OnlineStore.
+type OnlineStoreTable interface {
+	Set(ctx context.Context, entity string, value interface{}) error
+	Get(ctx context.Context, entity string) (interface{}, error)
+}
+
+// OnlineStore is the provider-agnostic interface for backends that serve
+// single-entity feature lookups at request time.
+type OnlineStore interface {
+	Provider
+	CreateTable(ctx context.Context, feature, variant string, valueType ValueType) (OnlineStoreTable, error)
+	GetTable(ctx context.Context, feature, variant string) (OnlineStoreTable, error)
+}
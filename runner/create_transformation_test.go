@@ -5,6 +5,7 @@
 package runner
 
 import (
+	"context"
 	"fmt"
 	"github.com/featureform/provider"
 	"testing"
@@ -14,60 +15,60 @@ type MockOfflineCreateTransformationFail struct {
 	provider.BaseProvider
 }
 
-func (m MockOfflineCreateTransformationFail) CreateResourceTable(provider.ResourceID, provider.TableSchema) (provider.OfflineTable, error) {
+func (m MockOfflineCreateTransformationFail) CreateResourceTable(ctx context.Context, id provider.ResourceID, schema provider.TableSchema) (provider.OfflineTable, error) {
 	return nil, nil
 }
-func (m MockOfflineCreateTransformationFail) GetResourceTable(id provider.ResourceID) (provider.OfflineTable, error) {
+func (m MockOfflineCreateTransformationFail) GetResourceTable(ctx context.Context, id provider.ResourceID) (provider.OfflineTable, error) {
 	return nil, nil
 }
-func (m MockOfflineCreateTransformationFail) CreateMaterialization(id provider.ResourceID) (provider.Materialization, error) {
+func (m MockOfflineCreateTransformationFail) CreateMaterialization(ctx context.Context, id provider.ResourceID) (provider.Materialization, error) {
 	return nil, nil
 }
-func (m MockOfflineCreateTransformationFail) GetMaterialization(id provider.MaterializationID) (provider.Materialization, error) {
+func (m MockOfflineCreateTransformationFail) GetMaterialization(ctx context.Context, id provider.MaterializationID) (provider.Materialization, error) {
 	return nil, nil
 }
-func (m MockOfflineCreateTransformationFail) DeleteMaterialization(id provider.MaterializationID) error {
+func (m MockOfflineCreateTransformationFail) DeleteMaterialization(ctx context.Context, id provider.MaterializationID) error {
 	return nil
 }
-func (m MockOfflineCreateTransformationFail) CreateTrainingSet(provider.TrainingSetDef) error {
+func (m MockOfflineCreateTransformationFail) CreateTrainingSet(ctx context.Context, def provider.TrainingSetDef) error {
 	return nil
 }
-func (m MockOfflineCreateTransformationFail) GetTrainingSet(id provider.ResourceID) (provider.TrainingSetIterator, error) {
+func (m MockOfflineCreateTransformationFail) GetTrainingSet(ctx context.Context, id provider.ResourceID) (provider.TrainingSetIterator, error) {
 	return nil, nil
 }
 
-func (m MockOfflineCreateTransformationFail) CreatePrimaryTable(id provider.ResourceID, schema provider.TableSchema) (provider.PrimaryTable, error) {
+func (m MockOfflineCreateTransformationFail) CreatePrimaryTable(ctx context.Context, id provider.ResourceID, schema provider.TableSchema) (provider.PrimaryTable, error) {
 	return nil, nil
 }
-func (m MockOfflineCreateTransformationFail) GetPrimaryTable(id provider.ResourceID) (provider.PrimaryTable, error) {
+func (m MockOfflineCreateTransformationFail) GetPrimaryTable(ctx context.Context, id provider.ResourceID) (provider.PrimaryTable, error) {
 	return nil, nil
 }
 
-func (m MockOfflineCreateTransformationFail) RegisterResourceFromSourceTable(id provider.ResourceID, schema provider.ResourceSchema) (provider.OfflineTable, error) {
+func (m MockOfflineCreateTransformationFail) RegisterResourceFromSourceTable(ctx context.Context, id provider.ResourceID, schema provider.ResourceSchema) (provider.OfflineTable, error) {
 	return nil, nil
 }
 
-func (m MockOfflineCreateTransformationFail) RegisterPrimaryFromSourceTable(id provider.ResourceID, sourceName string) (provider.PrimaryTable, error) {
+func (m MockOfflineCreateTransformationFail) RegisterPrimaryFromSourceTable(ctx context.Context, id provider.ResourceID, sourceName string) (provider.PrimaryTable, error) {
 	return nil, nil
 }
 
-func (m MockOfflineCreateTransformationFail) CreateTransformation(config provider.TransformationConfig) error {
+func (m MockOfflineCreateTransformationFail) CreateTransformation(ctx context.Context, config provider.TransformationConfig) error {
 	return fmt.Errorf("could not create training set")
 }
 
-func (m MockOfflineCreateTransformationFail) GetTransformationTable(id provider.ResourceID) (provider.TransformationTable, error) {
+func (m MockOfflineCreateTransformationFail) GetTransformationTable(ctx context.Context, id provider.ResourceID) (provider.TransformationTable, error) {
 	return nil, nil
 }
 
-func (m MockOfflineCreateTransformationFail) UpdateMaterialization(id provider.ResourceID) (provider.Materialization, error) {
+func (m MockOfflineCreateTransformationFail) UpdateMaterialization(ctx context.Context, id provider.ResourceID) (provider.Materialization, error) {
 	return nil, nil
 }
 
-func (m MockOfflineCreateTransformationFail) UpdateTransformation(config provider.TransformationConfig) error {
+func (m MockOfflineCreateTransformationFail) UpdateTransformation(ctx context.Context, config provider.TransformationConfig) error {
 	return nil
 }
 
-func (m MockOfflineCreateTransformationFail) UpdateTrainingSet(provider.TrainingSetDef) error {
+func (m MockOfflineCreateTransformationFail) UpdateTrainingSet(ctx context.Context, def provider.TrainingSetDef) error {
 	return nil
 }
 